@@ -0,0 +1,71 @@
+package pipelines
+
+import (
+	"context"
+	"time"
+)
+
+// Batch accumulates values received from in and emits a slice to its output channel once either size values have
+// been collected, or maxWait has elapsed since the first value of the current batch was received, whichever comes
+// first. The timer is reset when a batch is emitted, not on every item. A partial batch is emitted when in is
+// closed; Batch never emits an empty slice.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration, opts ...OptionFunc[[]T]) <-chan []T {
+	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- []T) {
+		doBatch(ctx, in, size, maxWait, out[0])
+	}, configure(opts)))
+}
+
+func doBatch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration, out chan<- []T) {
+	var batch []T
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+			timerC = nil
+		}
+	}
+	emit := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		toSend := batch
+		batch = nil
+		stopTimer()
+		select {
+		case <-ctx.Done():
+			return false
+		case out <- toSend:
+			return true
+		}
+	}
+	defer stopTimer()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timerC:
+			if !emit() {
+				return
+			}
+		case t, ok := <-in:
+			if !ok {
+				emit()
+				return
+			}
+			batch = append(batch, t)
+			if timer == nil {
+				timer = time.NewTimer(maxWait)
+				timerC = timer.C
+			}
+			if len(batch) >= size {
+				if !emit() {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pipelines
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatchEmitsOnSize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	in := Chan([]int{1, 2, 3, 4, 5, 6})
+	out := Batch(ctx, in, 2, time.Hour)
+
+	batches, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]int{{1, 2}, {3, 4}, {5, 6}}
+	if len(batches) != len(want) {
+		t.Fatalf("got %v, want %v", batches, want)
+	}
+	for i := range want {
+		if !intSliceEqual(batches[i], want[i]) {
+			t.Fatalf("batch %d: got %v, want %v", i, batches[i], want[i])
+		}
+	}
+}
+
+func TestBatchEmitsPartialTrailingBatchOnClose(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	in := Chan([]int{1, 2, 3})
+	out := Batch(ctx, in, 10, time.Hour)
+
+	batches, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 1 || !intSliceEqual(batches[0], []int{1, 2, 3}) {
+		t.Fatalf("got %v, want [[1 2 3]]", batches)
+	}
+}
+
+func TestBatchEmitsOnMaxWait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	in := make(chan int)
+	out := Batch(ctx, in, 10, 20*time.Millisecond)
+
+	go func() {
+		in <- 1
+		in <- 2
+		time.Sleep(100 * time.Millisecond)
+		close(in)
+	}()
+
+	batches, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 1 || !intSliceEqual(batches[0], []int{1, 2}) {
+		t.Fatalf("got %v, want a single batch [1 2] emitted on the timer", batches)
+	}
+}
+
+func TestBatchNeverEmitsEmptySlice(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	in := make(chan int) // never sent to, never closed until ctx expires
+	out := Batch(ctx, in, 10, 10*time.Millisecond)
+
+	batches, _ := Drain(ctx, out)
+	if len(batches) != 0 {
+		t.Fatalf("got %v, want no batches emitted for an empty input", batches)
+	}
+}
+
+func intSliceEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
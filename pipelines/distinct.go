@@ -0,0 +1,119 @@
+package pipelines
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Distinct suppresses values received from the input channel whose key, as computed by key, has already been seen.
+// The set of seen keys grows without bound for the lifetime of the stage; use DistinctLRU for long-running or
+// high-cardinality streams where an unbounded set is untenable.
+//
+// The set of seen keys is shared across all workers when run with WithPool, so duplicates are suppressed
+// pipeline-wide rather than per worker.
+func Distinct[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K, opts ...OptionFunc[T]) <-chan T {
+	var mu sync.Mutex
+	seen := make(map[K]struct{})
+	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+		doDistinct(ctx, in, out[0], func(t T) bool {
+			k := key(t)
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := seen[k]; ok {
+				return false
+			}
+			seen[k] = struct{}{}
+			return true
+		})
+	}, configure(opts)))
+}
+
+// DistinctCtx behaves like Distinct, except that the same context passed to DistinctCtx is passed as an argument to
+// key, for keys whose computation requires a lookup.
+func DistinctCtx[T any, K comparable](ctx context.Context, in <-chan T, key func(context.Context, T) K, opts ...OptionFunc[T]) <-chan T {
+	var mu sync.Mutex
+	seen := make(map[K]struct{})
+	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+		doDistinct(ctx, in, out[0], func(t T) bool {
+			k := key(ctx, t)
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := seen[k]; ok {
+				return false
+			}
+			seen[k] = struct{}{}
+			return true
+		})
+	}, configure(opts)))
+}
+
+// DistinctLRU behaves like Distinct, except that the set of seen keys is bounded to the provided size. Once the
+// bound is reached, the least recently seen key is evicted to make room, so a value may be re-emitted if its key
+// falls out of the cache before being seen again.
+func DistinctLRU[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K, size int, opts ...OptionFunc[T]) <-chan T {
+	cache := newLRUSet[K](size)
+	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+		doDistinct(ctx, in, out[0], func(t T) bool {
+			return cache.addIfAbsent(key(t))
+		})
+	}, configure(opts)))
+}
+
+func doDistinct[T any](ctx context.Context, in <-chan T, out chan<- T, isNew func(T) bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t, ok := <-in:
+			if !ok {
+				return
+			}
+			if !isNew(t) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- t:
+			}
+		}
+	}
+}
+
+// lruSet is a fixed-size set of recently seen keys, evicting the least recently seen key once full. It is safe for
+// concurrent use, since it is shared across all workers of a stage run with WithPool.
+type lruSet[K comparable] struct {
+	mu       sync.Mutex
+	size     int
+	elements map[K]*list.Element
+	order    *list.List // front = most recently seen, back = least recently seen
+}
+
+func newLRUSet[K comparable](size int) *lruSet[K] {
+	return &lruSet[K]{
+		size:     size,
+		elements: make(map[K]*list.Element, size),
+		order:    list.New(),
+	}
+}
+
+// addIfAbsent reports whether k had not already been seen, and records it as seen either way.
+func (c *lruSet[K]) addIfAbsent(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.elements[k]; ok {
+		c.order.MoveToFront(elem)
+		return false
+	}
+	if c.size > 0 && len(c.elements) >= c.size {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.elements, oldest.Value.(K))
+		}
+	}
+	c.elements[k] = c.order.PushFront(k)
+	return true
+}
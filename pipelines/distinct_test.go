@@ -0,0 +1,97 @@
+package pipelines
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDistinctSuppressesRepeatedKeys(t *testing.T) {
+	ctx := context.Background()
+	in := Chan([]int{1, 1, 2, 3, 2, 1, 4})
+
+	out := Distinct(ctx, in, func(i int) int { return i })
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{1, 2, 3, 4}
+	if !intSliceEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDistinctSharesStateAcrossPoolWorkers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	const perKey = 20
+	const keys = 10
+	ins := make([]int, 0, perKey*keys)
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			ins = append(ins, k)
+		}
+	}
+
+	out := Distinct(ctx, Chan(ins), func(i int) int { return i }, WithPool[int](4))
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != keys {
+		t.Fatalf("got %d distinct values across a worker pool, want %d (dedup state is not shared across workers)", len(got), keys)
+	}
+}
+
+func TestDistinctCtxUsesContextualKey(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, 100)
+	in := Chan([]int{1, 2, 1, 3})
+
+	out := DistinctCtx(ctx, in, func(ctx context.Context, i int) int {
+		offset, _ := ctx.Value(ctxKey{}).(int)
+		return i + offset
+	})
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{1, 2, 3}
+	if !intSliceEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDistinctLRUEvictsLeastRecentlySeen(t *testing.T) {
+	ctx := context.Background()
+	// Key 1 falls out of a size-2 cache after 2 and 3 are seen, so it is treated as new again.
+	in := Chan([]int{1, 2, 3, 1})
+
+	out := DistinctLRU(ctx, in, func(i int) int { return i }, 2)
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{1, 2, 3, 1}
+	if !intSliceEqual(got, want) {
+		t.Fatalf("got %v, want %v (LRU eviction should re-admit key 1)", got, want)
+	}
+}
+
+func TestDistinctLRURefreshesRecencyOnHit(t *testing.T) {
+	ctx := context.Background()
+	// Re-seeing key 1 before 3 arrives should move it to the front, so 2 (not 1) is evicted.
+	in := Chan([]int{1, 2, 1, 3, 2})
+
+	out := DistinctLRU(ctx, in, func(i int) int { return i }, 2)
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int{1, 2, 3, 2}
+	if !intSliceEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
@@ -0,0 +1,274 @@
+package pipelines
+
+import "context"
+
+// WithErrorChan configures a pipeline stage to send errors to the provided channel instead of creating and
+// returning its own. This makes it possible to merge the errors produced by several stages onto a single shared
+// channel without an extra Merge step. When this option is used, the error channel returned by the stage is nil.
+func WithErrorChan[T any](ch chan<- error) OptionFunc[T] {
+	return func(conf *config[T]) {
+		conf.errChan = ch
+	}
+}
+
+// prepareErrSink returns the channel an error-aware stage should send errors to: the channel supplied via
+// WithErrorChan, if any, or else a freshly created channel recorded on conf as conf.errOwned so that doWithConf and
+// doWithSemaphore close it once every worker has finished, alongside the stage's output channel(s).
+func prepareErrSink[T any](conf *config[T]) chan<- error {
+	if conf.errChan != nil {
+		return conf.errChan
+	}
+	conf.errOwned = make(chan error)
+	return conf.errOwned
+}
+
+// errOutOf returns the error channel to hand back to the caller of an error-aware stage: conf.errOwned, or nil if
+// WithErrorChan was supplied and the stage has no channel of its own to return.
+func errOutOf[T any](conf config[T]) <-chan error {
+	return conf.errOwned
+}
+
+// MapErr applies f to every value received from the input channel. Values are sent to the output channel; errors
+// are sent to the error channel. The error channel returned is nil if WithErrorChan was supplied as an option.
+// MapErr honors WithPool and WithSemaphore like Map.
+func MapErr[S, T any](ctx context.Context, in <-chan S, f func(S) (T, error), opts ...OptionFunc[T]) (<-chan T, <-chan error) {
+	conf := configure(opts)
+	errs := prepareErrSink(&conf)
+	var out <-chan T
+	if conf.semaphore > 0 {
+		out = return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			mapErrItem(ctx, s, f, out, errs)
+		}))
+	} else {
+		out = return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+			doMapErr(ctx, in, f, out[0], errs)
+		}, conf))
+	}
+	return out, errOutOf(conf)
+}
+
+func doMapErr[S, T any](ctx context.Context, in <-chan S, f func(S) (T, error), out chan<- T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s, ok := <-in:
+			if !ok {
+				return
+			}
+			mapErrItem(ctx, s, f, out, errs)
+		}
+	}
+}
+
+func mapErrItem[S, T any](ctx context.Context, s S, f func(S) (T, error), out chan<- T, errs chan<- error) {
+	t, err := f(s)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errs <- err:
+		}
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case out <- t:
+	}
+}
+
+// FlatMapErr applies f to every value received from the input channel and sends all values found in the slice
+// returned from f to the output channel. Errors are sent to the error channel in place of the slice. FlatMapErr
+// honors WithPool and WithSemaphore like FlatMap.
+func FlatMapErr[S, T any](ctx context.Context, in <-chan S, f func(S) ([]T, error), opts ...OptionFunc[T]) (<-chan T, <-chan error) {
+	conf := configure(opts)
+	errs := prepareErrSink(&conf)
+	var out <-chan T
+	if conf.semaphore > 0 {
+		out = return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			flatMapErrItem(ctx, s, f, out, errs)
+		}))
+	} else {
+		out = return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+			doFlatMapErr(ctx, in, f, out[0], errs)
+		}, conf))
+	}
+	return out, errOutOf(conf)
+}
+
+func doFlatMapErr[S, T any](ctx context.Context, in <-chan S, f func(S) ([]T, error), out chan<- T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s, ok := <-in:
+			if !ok {
+				return
+			}
+			flatMapErrItem(ctx, s, f, out, errs)
+		}
+	}
+}
+
+func flatMapErrItem[S, T any](ctx context.Context, s S, f func(S) ([]T, error), out chan<- T, errs chan<- error) {
+	ts, err := f(s)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errs <- err:
+		}
+		return
+	}
+	sendAll(ctx, ts, out)
+}
+
+// OptionMapErr applies f to every value received from the input channel and sends all non-nil results to the
+// output channel. Errors are sent to the error channel in place of the result. OptionMapErr honors WithPool and
+// WithSemaphore like OptionMap.
+func OptionMapErr[S, T any](ctx context.Context, in <-chan S, f func(S) (*T, error), opts ...OptionFunc[T]) (<-chan T, <-chan error) {
+	conf := configure(opts)
+	errs := prepareErrSink(&conf)
+	var out <-chan T
+	if conf.semaphore > 0 {
+		out = return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			optionMapErrItem(ctx, s, f, out, errs)
+		}))
+	} else {
+		out = return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+			doOptionMapErr(ctx, in, f, out[0], errs)
+		}, conf))
+	}
+	return out, errOutOf(conf)
+}
+
+func doOptionMapErr[S, T any](ctx context.Context, in <-chan S, f func(S) (*T, error), out chan<- T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s, ok := <-in:
+			if !ok {
+				return
+			}
+			optionMapErrItem(ctx, s, f, out, errs)
+		}
+	}
+}
+
+func optionMapErrItem[S, T any](ctx context.Context, s S, f func(S) (*T, error), out chan<- T, errs chan<- error) {
+	t, err := f(s)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errs <- err:
+		}
+		return
+	}
+	if t == nil {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case out <- *t:
+	}
+}
+
+// FilterErr applies f to every value received from the input channel and sends the value to the output channel
+// when f returns true. Errors are sent to the error channel and the value is dropped. FilterErr honors WithPool
+// and WithSemaphore like Map.
+func FilterErr[T any](ctx context.Context, in <-chan T, f func(T) (bool, error), opts ...OptionFunc[T]) (<-chan T, <-chan error) {
+	conf := configure(opts)
+	errs := prepareErrSink(&conf)
+	var out <-chan T
+	if conf.semaphore > 0 {
+		out = return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, t T, out chan<- T) {
+			filterErrItem(ctx, t, f, out, errs)
+		}))
+	} else {
+		out = return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+			doFilterErr(ctx, in, f, out[0], errs)
+		}, conf))
+	}
+	return out, errOutOf(conf)
+}
+
+func doFilterErr[T any](ctx context.Context, in <-chan T, f func(T) (bool, error), out chan<- T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t, ok := <-in:
+			if !ok {
+				return
+			}
+			filterErrItem(ctx, t, f, out, errs)
+		}
+	}
+}
+
+func filterErrItem[T any](ctx context.Context, t T, f func(T) (bool, error), out chan<- T, errs chan<- error) {
+	keep, err := f(t)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errs <- err:
+		}
+		return
+	}
+	if !keep {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case out <- t:
+	}
+}
+
+// DrainErr receives all values from in while concurrently watching errs, returning the values collected so far
+// together with the first error observed on errs, if any. DrainErr blocks the caller until in is closed, the
+// provided context is cancelled, or an error is received. To stop upstream stages promptly once an error is
+// observed, derive ctx from context.WithCancel and cancel it as soon as DrainErr returns a non-nil error.
+func DrainErr[T any](ctx context.Context, in <-chan T, errs <-chan error) ([]T, error) {
+	var result []T
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			return result, err
+		case t, ok := <-in:
+			if !ok {
+				return result, nil
+			}
+			result = append(result, t)
+		}
+	}
+}
+
+// ReduceErr runs a reducer function on every input received from in while concurrently watching errs, returning the
+// first error observed on errs, if any. ReduceErr blocks the caller until in is closed, the provided context is
+// cancelled, or an error is received. To stop upstream stages promptly once an error is observed, derive ctx from
+// context.WithCancel and cancel it as soon as ReduceErr returns a non-nil error.
+func ReduceErr[S, T any](ctx context.Context, in <-chan S, errs <-chan error, f func(T, S) T) (T, error) {
+	var result T
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			return result, err
+		case s, ok := <-in:
+			if !ok {
+				return result, nil
+			}
+			result = f(result, s)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapErrSplitsValuesAndErrors(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	in := Chan([]int{1, 2, 3, 4})
+
+	out, errs := MapErr(ctx, in, func(i int) (int, error) {
+		if i%2 == 0 {
+			return 0, boom
+		}
+		return i * 10, nil
+	})
+
+	var gotOut []int
+	var gotErrs []error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for out != nil || errs != nil {
+			select {
+			case v, ok := <-out:
+				if !ok {
+					out = nil
+					continue
+				}
+				gotOut = append(gotOut, v)
+			case e, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				gotErrs = append(gotErrs, e)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out draining MapErr output")
+	}
+
+	if len(gotOut) != 2 || len(gotErrs) != 2 {
+		t.Fatalf("got %d values and %d errors, want 2 and 2", len(gotOut), len(gotErrs))
+	}
+	for _, e := range gotErrs {
+		if !errors.Is(e, boom) {
+			t.Fatalf("got error %v, want %v", e, boom)
+		}
+	}
+}
+
+func TestMapErrWithErrorChanMerges(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	shared := make(chan error, 4)
+
+	out1, nilErrs1 := MapErr(ctx, Chan([]int{1, 2}), func(i int) (int, error) {
+		return 0, boom
+	}, WithErrorChan[int](shared))
+	out2, nilErrs2 := MapErr(ctx, Chan([]int{3, 4}), func(i int) (int, error) {
+		return 0, boom
+	}, WithErrorChan[int](shared))
+
+	if nilErrs1 != nil || nilErrs2 != nil {
+		t.Fatalf("expected nil error channels when WithErrorChan is supplied")
+	}
+	if _, err := Drain(ctx, out1); err != nil {
+		t.Fatalf("unexpected error draining out1: %v", err)
+	}
+	if _, err := Drain(ctx, out2); err != nil {
+		t.Fatalf("unexpected error draining out2: %v", err)
+	}
+	close(shared)
+
+	count := 0
+	for range shared {
+		count++
+	}
+	if count != 4 {
+		t.Fatalf("got %d merged errors, want 4", count)
+	}
+}
+
+func TestFilterErrKeepsOnlyPassingValues(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+	in := Chan([]int{1, 2, 3, 4, 5})
+
+	out, errs := FilterErr(ctx, in, func(i int) (bool, error) {
+		if i == 3 {
+			return false, boom
+		}
+		return i%2 == 1, nil
+	})
+
+	var gotErrs []error
+	errsDone := make(chan struct{})
+	go func() {
+		defer close(errsDone)
+		for e := range errs {
+			gotErrs = append(gotErrs, e)
+		}
+	}()
+
+	vals, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-errsDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out draining errs")
+	}
+
+	if len(vals) != 2 || vals[0] != 1 || vals[1] != 5 {
+		t.Fatalf("got %v, want [1 5]", vals)
+	}
+	if len(gotErrs) != 1 || !errors.Is(gotErrs[0], boom) {
+		t.Fatalf("got errors %v, want [%v]", gotErrs, boom)
+	}
+}
+
+func TestDrainErrReturnsFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	ctx := context.Background()
+	in := make(chan int)
+	errs := make(chan error, 1)
+	go func() {
+		in <- 1
+		errs <- boom
+	}()
+
+	vals, err := DrainErr(ctx, in, errs)
+	if !errors.Is(err, boom) {
+		t.Fatalf("got error %v, want %v", err, boom)
+	}
+	if len(vals) != 1 || vals[0] != 1 {
+		t.Fatalf("got %v, want [1]", vals)
+	}
+}
+
+func TestMapErrHonorsPoolConcurrency(t *testing.T) {
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 40)
+	for i := range ins {
+		ins[i] = i
+	}
+	var cur, max int64
+	release := make(chan struct{})
+	var closeOnce int32
+
+	out, errs := MapErr(ctx, Chan(ins), func(i int) (int, error) {
+		c := atomic.AddInt64(&cur, 1)
+		casMax(&max, c)
+		if int(c) == n && atomic.CompareAndSwapInt32(&closeOnce, 0, 1) {
+			close(release)
+		}
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		atomic.AddInt64(&cur, -1)
+		return i, nil
+	}, WithPool[int](n))
+
+	go func() {
+		for range errs {
+		}
+	}()
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got < n {
+		t.Fatalf("observed max concurrency %d, want at least %d (WithPool is not being honored)", got, n)
+	}
+}
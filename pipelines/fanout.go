@@ -0,0 +1,148 @@
+package pipelines
+
+import (
+	"context"
+	"reflect"
+)
+
+// A FanOutMode selects how FanOut distributes values received from its input channel across its output channels.
+type FanOutMode int
+
+const (
+	// RoundRobin sends each value received from the input channel to exactly one output channel, cycling through
+	// the outputs in order. This is the default mode.
+	RoundRobin FanOutMode = iota
+	// Broadcast sends each value received from the input channel to every output channel, as Tee does for 2
+	// outputs.
+	Broadcast
+)
+
+// A DropPolicy selects how Broadcast mode behaves when an output channel is not ready to receive. It has no effect
+// in RoundRobin mode.
+type DropPolicy int
+
+const (
+	// BlockSlowConsumers makes Broadcast wait for every output channel to be ready before proceeding to the next
+	// value. This is the default policy, and matches the behavior of Tee.
+	BlockSlowConsumers DropPolicy = iota
+	// DropForSlowConsumers makes Broadcast send to whichever output channels are immediately ready, dropping the
+	// value for any output channel that is not. Combine with WithBuffer to give slow consumers some slack before
+	// values are dropped for them.
+	DropForSlowConsumers
+)
+
+// WithFanOutMode selects the distribution mode used by FanOut. The default is RoundRobin.
+func WithFanOutMode[T any](mode FanOutMode) OptionFunc[T] {
+	return func(conf *config[T]) {
+		conf.fanOutMode = mode
+	}
+}
+
+// WithDropPolicy selects the policy FanOut uses to decouple slow consumers when run in Broadcast mode. The default
+// is BlockSlowConsumers.
+func WithDropPolicy[T any](policy DropPolicy) OptionFunc[T] {
+	return func(conf *config[T]) {
+		conf.dropPolicy = policy
+	}
+}
+
+// FanOut sends values received from the input channel to n output channels, according to the configured
+// FanOutMode. In RoundRobin mode (the default) each value is sent to exactly one output channel; in Broadcast mode
+// each value is sent to every output channel, generalizing Tee to n outputs.
+func FanOut[T any](ctx context.Context, in <-chan T, n int, opts ...OptionFunc[T]) []<-chan T {
+	if n <= 0 {
+		panic("pipelines: FanOut requires n > 0")
+	}
+	conf := configure(opts)
+	conf.outputs = n
+	return doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+		if conf.fanOutMode == Broadcast {
+			doBroadcast(ctx, in, out, conf.dropPolicy)
+		} else {
+			doRoundRobin(ctx, in, out)
+		}
+	}, conf)
+}
+
+func doRoundRobin[T any](ctx context.Context, in <-chan T, outs []chan<- T) {
+	i := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t, ok := <-in:
+			if !ok {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case outs[i] <- t:
+			}
+			i = (i + 1) % len(outs)
+		}
+	}
+}
+
+func doBroadcast[T any](ctx context.Context, in <-chan T, outs []chan<- T, policy DropPolicy) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t, ok := <-in:
+			if !ok {
+				return
+			}
+			for _, out := range outs {
+				if policy == DropForSlowConsumers {
+					select {
+					case out <- t:
+					default:
+					}
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- t:
+				}
+			}
+		}
+	}
+}
+
+// Merge sends all values received from every one of ins to its output channel, generalizing Combine to an
+// arbitrary number of input channels. The output channel is closed once every input channel has been closed or the
+// provided context is cancelled.
+func Merge[T any](ctx context.Context, ins []<-chan T, opts ...OptionFunc[T]) <-chan T {
+	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+		doMerge(ctx, ins, out[0])
+	}, configure(opts)))
+}
+
+func doMerge[T any](ctx context.Context, ins []<-chan T, out chan<- T) {
+	cases := make([]reflect.SelectCase, len(ins)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for i, in := range ins {
+		cases[i+1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in)}
+	}
+
+	remaining := len(ins)
+	for remaining > 0 {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return
+		}
+		if !ok {
+			// Disable this input by replacing it with a nil channel, which is never ready to receive.
+			cases[chosen].Chan = reflect.ValueOf((<-chan T)(nil))
+			remaining--
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case out <- value.Interface().(T):
+		}
+	}
+}
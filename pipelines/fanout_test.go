@@ -0,0 +1,143 @@
+package pipelines
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestFanOutPanicsOnNonPositiveN(t *testing.T) {
+	ctx := context.Background()
+	in := Chan([]int{1, 2, 3})
+
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("FanOut(n=%d) did not panic", n)
+				}
+			}()
+			FanOut(ctx, in, n)
+		}()
+	}
+}
+
+func TestFanOutRoundRobinAcrossNOutputs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	const n = 3
+	ins := make([]int, 9)
+	for i := range ins {
+		ins[i] = i
+	}
+	outs := FanOut(ctx, Chan(ins), n)
+	if len(outs) != n {
+		t.Fatalf("got %d output channels, want %d", len(outs), n)
+	}
+
+	// Outputs must be drained concurrently: round-robin blocks on each output in turn until that output receives, so
+	// draining one output at a time would stall as soon as the next value is due on another output.
+	type drained struct {
+		vals []int
+		err  error
+	}
+	results := make(chan drained, n)
+	for _, out := range outs {
+		go func(out <-chan int) {
+			vals, err := Drain(ctx, out)
+			results <- drained{vals, err}
+		}(out)
+	}
+
+	var all []int
+	for range outs {
+		d := <-results
+		if d.err != nil {
+			t.Fatalf("unexpected error: %v", d.err)
+		}
+		all = append(all, d.vals...)
+	}
+	sort.Ints(all)
+	for i, v := range all {
+		if v != i {
+			t.Fatalf("got %v, want every input exactly once", all)
+		}
+	}
+}
+
+func TestFanOutBroadcastSendsToEveryOutput(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	const n = 4
+	outs := FanOut(ctx, Chan([]int{1, 2, 3}), n, WithFanOutMode[int](Broadcast), WithBuffer[int](3))
+	if len(outs) != n {
+		t.Fatalf("got %d output channels, want %d", len(outs), n)
+	}
+	for i, out := range outs {
+		got, err := Drain(ctx, out)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !intSliceEqual(got, []int{1, 2, 3}) {
+			t.Fatalf("output %d: got %v, want [1 2 3]", i, got)
+		}
+	}
+}
+
+func TestMergeCombinesMoreThanTwoChannels(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := []<-chan int{
+		Chan([]int{1, 2}),
+		Chan([]int{3, 4}),
+		Chan([]int{5, 6}),
+		Chan([]int{7, 8}),
+	}
+	out := Merge(ctx, ins)
+
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Ints(got)
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	if !intSliceEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMergeWithZeroChannelsClosesImmediately(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	out := Merge[int](ctx, nil)
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("got %v, want no values from an empty Merge", got)
+	}
+}
+
+// Merge's opts must come after ins as a trailing variadic, consistent with every other OptionFunc-accepting stage in
+// this package (e.g. FanOut in this file).
+func TestMergeAcceptsTrailingOptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := []<-chan int{Chan([]int{1}), Chan([]int{2})}
+	out := Merge(ctx, ins, WithBuffer[int](2))
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Ints(got)
+	if !intSliceEqual(got, []int{1, 2}) {
+		t.Fatalf("got %v, want [1 2]", got)
+	}
+}
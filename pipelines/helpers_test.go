@@ -0,0 +1,17 @@
+package pipelines
+
+import "sync/atomic"
+
+// casMax atomically sets *max to c if c is larger than the current value, retrying under contention. It is shared
+// by tests that assert on observed concurrency.
+func casMax(max *int64, c int64) {
+	for {
+		m := atomic.LoadInt64(max)
+		if c <= m {
+			return
+		}
+		if atomic.CompareAndSwapInt64(max, m, c) {
+			return
+		}
+	}
+}
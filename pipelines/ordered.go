@@ -0,0 +1,129 @@
+package pipelines
+
+import (
+	"container/heap"
+	"context"
+)
+
+// OrderedMap behaves like Map run with WithPool, except that results are emitted on the output channel in the same
+// order as the corresponding inputs were received, regardless of which worker finished first.
+func OrderedMap[S, T any](ctx context.Context, in <-chan S, f func(S) T, opts ...OptionFunc[T]) <-chan T {
+	return OrderedMapCtx(ctx, in, func(_ context.Context, s S) T { return f(s) }, opts...)
+}
+
+// OrderedMapCtx behaves like MapCtx run with WithPool, except that results are emitted on the output channel in the
+// same order as the corresponding inputs were received, regardless of which worker finished first.
+//
+// Implementation: each input is tagged with a monotonic sequence number and dispatched to the worker pool. Completed
+// results are buffered in a min-heap keyed by sequence number, and the longest contiguous prefix is emitted to the
+// output channel as it becomes available.
+func OrderedMapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S) T, opts ...OptionFunc[T]) <-chan T {
+	conf := configure(opts)
+	out := conf.channer()
+
+	tagged := make(chan seqItem[S])
+	go func() {
+		defer close(tagged)
+		var seq uint64
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case tagged <- seqItem[S]{seq: seq, val: s}:
+					seq++
+				}
+			}
+		}
+	}()
+
+	results := doWithConf(ctx, func(ctx context.Context, results ...chan<- seqItem[T]) {
+		doOrderedMapCtx(ctx, tagged, f, results[0])
+	}, config[seqItem[T]]{
+		channer: func() chan seqItem[T] { return make(chan seqItem[T]) },
+		workers: conf.workers,
+		outputs: 1,
+	})
+
+	go func() {
+		if conf.doneCancel != nil {
+			defer conf.doneCancel()
+		}
+		defer close(out)
+		doReorder(ctx, results[0], out)
+	}()
+	return out
+}
+
+type seqItem[T any] struct {
+	seq uint64
+	val T
+}
+
+func doOrderedMapCtx[S, T any](ctx context.Context, in <-chan seqItem[S], f func(context.Context, S) T, out chan<- seqItem[T]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-in:
+			if !ok {
+				return
+			}
+			result := seqItem[T]{seq: item.seq, val: f(ctx, item.val)}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- result:
+			}
+		}
+	}
+}
+
+// seqHeap is a min-heap of seqItem ordered by sequence number.
+type seqHeap[T any] []seqItem[T]
+
+func (h seqHeap[T]) Len() int            { return len(h) }
+func (h seqHeap[T]) Less(i, j int) bool  { return h[i].seq < h[j].seq }
+func (h seqHeap[T]) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *seqHeap[T]) Push(x interface{}) { *h = append(*h, x.(seqItem[T])) }
+func (h *seqHeap[T]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// doReorder buffers out-of-order results in a min-heap keyed by sequence number and emits the longest contiguous
+// prefix available, in order, as results arrive.
+func doReorder[T any](ctx context.Context, in <-chan seqItem[T], out chan<- T) {
+	pending := &seqHeap[T]{}
+	var next uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-in:
+			if !ok {
+				return
+			}
+			heap.Push(pending, item)
+			for pending.Len() > 0 && (*pending)[0].seq == next {
+				ready := heap.Pop(pending).(seqItem[T])
+				select {
+				case <-ctx.Done():
+					return
+				case out <- ready.val:
+				}
+				next++
+			}
+		}
+	}
+}
+
@@ -0,0 +1,61 @@
+package pipelines
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestOrderedMapPreservesInputOrderUnderPool(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const count = 200
+	ins := make([]int, count)
+	for i := range ins {
+		ins[i] = i
+	}
+
+	out := OrderedMap(ctx, Chan(ins), func(i int) int {
+		// Vary processing time so faster workers would otherwise overtake slower ones.
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		return i * 2
+	}, WithPool[int](8))
+
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != count {
+		t.Fatalf("got %d results, want %d", len(got), count)
+	}
+	for i, v := range got {
+		if v != i*2 {
+			t.Fatalf("result %d out of order: got %d, want %d", i, v, i*2)
+		}
+	}
+}
+
+func TestOrderedMapCtxPreservesOrderSingleWorker(t *testing.T) {
+	ctx := context.Background()
+	ins := []string{"a", "b", "c", "d"}
+
+	out := OrderedMapCtx(ctx, Chan(ins), func(ctx context.Context, s string) string {
+		return s + s
+	})
+
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"aa", "bb", "cc", "dd"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
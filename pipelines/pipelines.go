@@ -25,6 +25,11 @@ type config[T any] struct {
 	workers    int
 	outputs    int
 	doneCancel context.CancelFunc
+	errChan    chan<- error
+	errOwned   chan error
+	semaphore  int
+	fanOutMode FanOutMode
+	dropPolicy DropPolicy
 }
 
 func (c config[T]) makeOutputs() []chan T {
@@ -73,6 +78,17 @@ func WithPool[T any](numWorkers int) OptionFunc[T] {
 	}
 }
 
+// WithSemaphore configures a pipeline stage to dispatch each value received from its input channel onto its own
+// goroutine, bounding the number running concurrently to n using a semaphore rather than a fixed pool of
+// long-lived workers. Unlike WithPool, a single slow item cannot block other, faster items queued behind it on the
+// same worker. WithSemaphore is supported by Map, MapCtx, OptionMap, ForkMapCtx, MapErr, FlatMapErr, OptionMapErr,
+// FilterErr, and Retry.
+func WithSemaphore[T any](n int) OptionFunc[T] {
+	return func(conf *config[T]) {
+		conf.semaphore = n
+	}
+}
+
 // WithDone returns a context which is cancelled when all goroutines started by this pipeline stage have shutdown.
 func WithDone[T any](ctx context.Context) (OptionFunc[T], context.Context) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -129,9 +145,18 @@ func doFlatten[T any](ctx context.Context, in <-chan []T, result chan<- T) {
 // Map applies f to every value received from the input channel and sends the result to the output channel.
 // The output channel is closed when the input channel is closed or the provided context is cancelled.
 func Map[S, T any](ctx context.Context, in <-chan S, f func(S) T, opts ...OptionFunc[T]) <-chan T {
+	conf := configure(opts)
+	if conf.semaphore > 0 {
+		return return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			select {
+			case <-ctx.Done():
+			case out <- f(s):
+			}
+		}))
+	}
 	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
 		doMap(ctx, in, f, out[0])
-	}, configure(opts)))
+	}, conf))
 }
 
 func return1[T any](chans []<-chan T) <-chan T {
@@ -163,9 +188,18 @@ func doMap[S, T any](ctx context.Context, in <-chan S, f func(S) T, result chan<
 // MapCtx applies f to every value received from its input channel and sends the result to its output channel.
 // The same context passed to MapCtx is passed as an argument to f.
 func MapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S) T, opts ...OptionFunc[T]) <-chan T {
+	conf := configure(opts)
+	if conf.semaphore > 0 {
+		return return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			select {
+			case <-ctx.Done():
+			case out <- f(ctx, s):
+			}
+		}))
+	}
 	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
 		doMapCtx(ctx, in, f, out[0])
-	}, configure(opts)))
+	}, conf))
 }
 
 func doMapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S) T, result chan<- T) {
@@ -329,9 +363,22 @@ func doWithCancel[T any](ctx context.Context, ch <-chan T, out chan<- T) {
 
 // OptionMap applies f to every value received from in and sends all non-nil results to its output channel.
 func OptionMap[S, T any](ctx context.Context, in <-chan S, f func(S) *T, opts ...OptionFunc[T]) <-chan T {
+	conf := configure(opts)
+	if conf.semaphore > 0 {
+		return return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			t := f(s)
+			if t == nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+			case out <- *t:
+			}
+		}))
+	}
 	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
 		doOptionMap(ctx, in, out[0], f)
-	}, configure(opts)))
+	}, conf))
 }
 
 func doOptionMap[S, T any](ctx context.Context, in <-chan S, out chan<- T, f func(S) *T) {
@@ -404,14 +451,19 @@ func sendAll[T any](ctx context.Context, ts []T, ch chan<- T) {
 // The same context passed to ForkMapCtx is passed to f.
 //
 // ForkMapCtx should be used with caution, as it introduces potentially unbounded parallelism to a pipeline computation.
+// Pass WithSemaphore to bound the number of goroutines running concurrently.
 //
 // Variants of ForkMapCtx are intentionally omitted from this package.
 // ForkMap is omitted because the caller cannot listen for context cancellation in some cases.
 // ForkFlatMap is omitted because it is more efficient for the caller range over the slice and send individual values themselves.
 func ForkMapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S, chan<- T), opts ...OptionFunc[T]) <-chan T {
+	conf := configure(opts)
+	if conf.semaphore > 0 {
+		return return1(doWithSemaphore(ctx, in, conf, f))
+	}
 	return return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
 		doForkMapCtx(ctx, in, f, out[0])
-	}, configure(opts)))
+	}, conf))
 }
 
 func doForkMapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S, chan<- T), out chan<- T) {
@@ -435,6 +487,77 @@ func doForkMapCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Con
 	}
 }
 
+// semaphore is a minimal counting semaphore built on a buffered channel, used by WithSemaphore to bound the number
+// of per-item goroutines running concurrently without requiring a fixed pool of long-lived workers.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	return make(semaphore, n)
+}
+
+// acquire blocks until a slot is free or ctx is cancelled, returning false in the latter case.
+func (s semaphore) acquire(ctx context.Context) bool {
+	select {
+	case s <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
+
+// doWithSemaphore dispatches f on its own goroutine for every value received from in, bounding concurrently running
+// goroutines to conf.semaphore. It is the semaphore-backed counterpart to doWithConf's worker pool.
+func doWithSemaphore[S, T any](ctx context.Context, in <-chan S, conf config[T], f func(context.Context, S, chan<- T)) []<-chan T {
+	outs := conf.makeOutputs()
+	go func() {
+		if conf.doneCancel != nil {
+			defer conf.doneCancel()
+		}
+		defer closeOuts(outs, conf)
+
+		out := sendOnly(outs)[0]
+		sem := newSemaphore(conf.semaphore)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s, ok := <-in:
+				if !ok {
+					return
+				}
+				if !sem.acquire(ctx) {
+					return
+				}
+				wg.Add(1)
+				go func(s S) {
+					defer wg.Done()
+					defer sem.release()
+					f(ctx, s, out)
+				}(s)
+			}
+		}
+	}()
+	return recvOnly(outs)
+}
+
+// closeOuts closes every channel in outs, along with conf.errOwned if this stage owns an error channel (see
+// WithErrorChan). It is the common close path shared by doWithConf and doWithSemaphore.
+func closeOuts[T any](outs []chan T, conf config[T]) {
+	for _, ch := range outs {
+		close(ch)
+	}
+	if conf.errOwned != nil {
+		close(conf.errOwned)
+	}
+}
+
 // doWithConf runs the implementation provided via doIt on goroutines according to the provided options.
 func doWithConf[T any](ctx context.Context, doIt func(context.Context, ...chan<- T), conf config[T]) []<-chan T {
 	outs := conf.makeOutputs()
@@ -443,11 +566,7 @@ func doWithConf[T any](ctx context.Context, doIt func(context.Context, ...chan<-
 			if conf.doneCancel != nil {
 				defer conf.doneCancel()
 			}
-			defer func() {
-				for _, ch := range outs {
-					close(ch)
-				}
-			}()
+			defer closeOuts(outs, conf)
 			doIt(ctx, sendOnly(outs)...)
 		}()
 	} else {
@@ -459,11 +578,7 @@ func doWithConf[T any](ctx context.Context, doIt func(context.Context, ...chan<-
 					wg.Done()
 					if id == 0 { // first thread closes the output channel.
 						wg.Wait()
-						defer func() {
-							for _, ch := range outs {
-								close(ch)
-							}
-						}()
+						defer closeOuts(outs, conf)
 						if conf.doneCancel != nil {
 							conf.doneCancel()
 						}
@@ -526,10 +641,34 @@ func Drain[T any](ctx context.Context, in <-chan T) ([]T, error) {
 }
 
 // Reduce runs a reducer function on every input received from the in chan and returns the output. Reduce blocks the
-// caller until the input channel is closed or the provided context is cancelled.
+// caller until the input channel is closed or the provided context is cancelled. The accumulator starts from the
+// zero value of T; use Fold if the reduction needs a different initial value.
 // An error is returned if and only if the provided context was cancelled before the input channel was closed.
-func Reduce[S, T string](ctx context.Context, in <-chan S, f func(T, S) T) (T, error) {
+func Reduce[S, T any](ctx context.Context, in <-chan S, f func(T, S) T) (T, error) {
+	var zero T
+	return Fold(ctx, in, zero, f)
+}
+
+// ReduceCtx behaves like Reduce, except that the same context passed to ReduceCtx is passed as an argument to f.
+func ReduceCtx[S, T any](ctx context.Context, in <-chan S, f func(context.Context, T, S) T) (T, error) {
 	var result T
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case s, ok := <-in:
+			if !ok {
+				return result, nil
+			}
+			result = f(ctx, result, s)
+		}
+	}
+}
+
+// Fold behaves like Reduce, except that the accumulator starts from the provided initial value instead of the zero
+// value of T.
+func Fold[S, T any](ctx context.Context, in <-chan S, init T, f func(T, S) T) (T, error) {
+	result := init
 	for {
 		select {
 		case <-ctx.Done():
@@ -0,0 +1,189 @@
+package pipelines
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockUntilConcurrent blocks the calling goroutine until n callers are blocked here simultaneously (releasing them
+// all at once), recording the maximum observed concurrency in max. It is used to assert that a stage is actually
+// running callers concurrently rather than serializing them.
+func blockUntilConcurrent(ctx context.Context, n int, cur, max *int64, release chan struct{}, closeOnce *int32) {
+	c := atomic.AddInt64(cur, 1)
+	casMax(max, c)
+	if int(c) == n && atomic.CompareAndSwapInt32(closeOnce, 0, 1) {
+		close(release)
+	}
+	select {
+	case <-release:
+	case <-ctx.Done():
+	}
+	atomic.AddInt64(cur, -1)
+}
+
+func TestMapHonorsSemaphoreConcurrency(t *testing.T) {
+	const n = 8
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 50)
+	for i := range ins {
+		ins[i] = i
+	}
+	var cur, max int64
+	var closeOnce int32
+	release := make(chan struct{})
+
+	out := Map(ctx, Chan(ins), func(i int) int {
+		blockUntilConcurrent(ctx, n, &cur, &max, release, &closeOnce)
+		return i
+	}, WithSemaphore[int](n))
+
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got < n {
+		t.Fatalf("observed max concurrency %d, want at least %d", got, n)
+	}
+}
+
+func TestMapCtxHonorsSemaphoreConcurrency(t *testing.T) {
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 20)
+	var cur, max int64
+	var closeOnce int32
+	release := make(chan struct{})
+
+	out := MapCtx(ctx, Chan(ins), func(ctx context.Context, i int) int {
+		blockUntilConcurrent(ctx, n, &cur, &max, release, &closeOnce)
+		return i
+	}, WithSemaphore[int](n))
+
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got < n {
+		t.Fatalf("observed max concurrency %d, want at least %d", got, n)
+	}
+}
+
+func TestOptionMapHonorsSemaphoreConcurrency(t *testing.T) {
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 20)
+	var cur, max int64
+	var closeOnce int32
+	release := make(chan struct{})
+
+	out := OptionMap(ctx, Chan(ins), func(i int) *int {
+		blockUntilConcurrent(ctx, n, &cur, &max, release, &closeOnce)
+		return &i
+	}, WithSemaphore[int](n))
+
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got < n {
+		t.Fatalf("observed max concurrency %d, want at least %d", got, n)
+	}
+}
+
+func TestForkMapCtxHonorsSemaphoreBound(t *testing.T) {
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 20)
+	var cur, max int64
+
+	out := ForkMapCtx(ctx, Chan(ins), func(ctx context.Context, i int, out chan<- int) {
+		c := atomic.AddInt64(&cur, 1)
+		casMax(&max, c)
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt64(&cur, -1)
+		select {
+		case out <- i:
+		case <-ctx.Done():
+		}
+	}, WithSemaphore[int](n))
+
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got > n {
+		t.Fatalf("observed max concurrency %d, want at most %d", got, n)
+	}
+}
+
+func TestReduceSumsFromZeroValue(t *testing.T) {
+	ctx := context.Background()
+	sum, err := Reduce(ctx, Chan([]int{1, 2, 3, 4}), func(acc, v int) int { return acc + v })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum != 10 {
+		t.Fatalf("got %d, want 10", sum)
+	}
+}
+
+func TestReduceWorksForNonStringTypes(t *testing.T) {
+	ctx := context.Background()
+	type total struct{ count, sum int }
+	in := Chan([]int{1, 2, 3})
+	got, err := Reduce(ctx, in, func(acc total, v int) total {
+		return total{count: acc.count + 1, sum: acc.sum + v}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.count != 3 || got.sum != 6 {
+		t.Fatalf("got %+v, want {count:3 sum:6}", got)
+	}
+}
+
+func TestFoldUsesProvidedInitialValue(t *testing.T) {
+	ctx := context.Background()
+	product, err := Fold(ctx, Chan([]int{1, 2, 3}), 10, func(acc, v int) int { return acc * v })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product != 60 {
+		t.Fatalf("got %d, want 60", product)
+	}
+}
+
+func TestReduceCtxReceivesContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, 100)
+	in := Chan([]int{1, 2, 3})
+
+	got, err := ReduceCtx(ctx, in, func(ctx context.Context, acc, v int) int {
+		offset, _ := ctx.Value(ctxKey{}).(int)
+		return acc + v + offset
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 3 items, each contributing +100 from the context plus its own value.
+	if want := 300 + 1 + 2 + 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestReduceReturnsCtxErrOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	in := make(chan int) // never sent to, never closed
+
+	_, err := Reduce(ctx, in, func(acc, v int) int { return acc + v })
+	if err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
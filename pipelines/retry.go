@@ -0,0 +1,131 @@
+package pipelines
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// A RetryPolicy controls how Retry retries a failing invocation of f.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times f is invoked for a single item, including the first attempt. A
+	// value <= 0 is treated as 1 (no retries).
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry.
+	InitialDelay time.Duration
+	// Multiplier scales the delay after each failed attempt. A value <= 0 disables backoff, and every retry waits
+	// InitialDelay.
+	Multiplier float64
+	// Jitter is the fraction of the computed delay, in either direction, by which the actual delay is randomized.
+	// For example, a Jitter of 0.1 means the actual delay is the computed delay +/- 10%. A value <= 0 disables
+	// jitter.
+	Jitter float64
+	// IsRetryable reports whether an error returned by f should be retried. A nil IsRetryable treats every error as
+	// retryable.
+	IsRetryable func(error) bool
+}
+
+// Retry applies f to every value received from the input channel, retrying failed invocations according to policy.
+// Successful results are sent to the output channel. If every attempt for a given item fails, the item's last error
+// is sent to the error channel, mirroring the behavior of MapErr. The error channel returned is nil if
+// WithErrorChan was supplied as an option.
+//
+// Retry honors WithPool and WithSemaphore like Map; WithSemaphore is the more natural fit here, since it dispatches
+// each item (and its retry delays) onto its own goroutine instead of letting a backing-off item stall a fixed
+// worker.
+func Retry[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S) (T, error), policy RetryPolicy, opts ...OptionFunc[T]) (<-chan T, <-chan error) {
+	conf := configure(opts)
+	errs := prepareErrSink(&conf)
+	var out <-chan T
+	if conf.semaphore > 0 {
+		out = return1(doWithSemaphore(ctx, in, conf, func(ctx context.Context, s S, out chan<- T) {
+			retryItem(ctx, s, f, policy, out, errs)
+		}))
+	} else {
+		out = return1(doWithConf(ctx, func(ctx context.Context, out ...chan<- T) {
+			doRetry(ctx, in, f, policy, out[0], errs)
+		}, conf))
+	}
+	return out, errOutOf(conf)
+}
+
+func doRetry[S, T any](ctx context.Context, in <-chan S, f func(context.Context, S) (T, error), policy RetryPolicy, out chan<- T, errs chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case s, ok := <-in:
+			if !ok {
+				return
+			}
+			retryItem(ctx, s, f, policy, out, errs)
+		}
+	}
+}
+
+func retryItem[S, T any](ctx context.Context, s S, f func(context.Context, S) (T, error), policy RetryPolicy, out chan<- T, errs chan<- error) {
+	t, err := runWithRetry(ctx, s, f, policy)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errs <- err:
+		}
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case out <- t:
+	}
+}
+
+// runWithRetry invokes f for s, retrying according to policy until it succeeds, a non-retryable error is returned,
+// attempts are exhausted, or ctx is cancelled.
+func runWithRetry[S, T any](ctx context.Context, s S, f func(context.Context, S) (T, error), policy RetryPolicy) (T, error) {
+	attempts := policy.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	delay := policy.InitialDelay
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		t, err := f(ctx, s)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+		if attempt == attempts-1 || (policy.IsRetryable != nil && !policy.IsRetryable(err)) {
+			break
+		}
+		if !sleep(ctx, withJitter(delay, policy.Jitter)) {
+			var zero T
+			return zero, ctx.Err()
+		}
+		if policy.Multiplier > 0 {
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+		}
+	}
+	var zero T
+	return zero, lastErr
+}
+
+// sleep waits for d or until ctx is cancelled, reporting which occurred first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// withJitter randomizes d by up to +/- jitter as a fraction of d. A jitter <= 0 returns d unchanged.
+func withJitter(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := float64(d) * jitter
+	offset := (rand.Float64()*2 - 1) * delta
+	return time.Duration(float64(d) + offset)
+}
@@ -0,0 +1,148 @@
+package pipelines
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	boom := errors.New("transient")
+	var calls int64
+	out, errs := Retry(ctx, Chan([]int{1}), func(ctx context.Context, i int) (int, error) {
+		n := atomic.AddInt64(&calls, 1)
+		if n < 3 {
+			return 0, boom
+		}
+		return i * 100, nil
+	}, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond})
+
+	go func() {
+		for range errs {
+		}
+	}()
+	got, err := Drain(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != 100 {
+		t.Fatalf("got %v, want [100]", got)
+	}
+	if atomic.LoadInt64(&calls) != 3 {
+		t.Fatalf("got %d calls, want exactly 3", calls)
+	}
+}
+
+func TestRetryEmitsErrorAfterMaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	boom := errors.New("always fails")
+	var calls int64
+	out, errs := Retry(ctx, Chan([]int{1}), func(ctx context.Context, i int) (int, error) {
+		atomic.AddInt64(&calls, 1)
+		return 0, boom
+	}, RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	go func() {
+		for range out {
+		}
+	}()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, boom) {
+			t.Fatalf("got error %v, want %v", err, boom)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for an error")
+	}
+	if atomic.LoadInt64(&calls) != 3 {
+		t.Fatalf("got %d calls, want exactly MaxAttempts (3)", calls)
+	}
+}
+
+func TestRetryDoesNotRetryNonRetryableErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	permanent := errors.New("permanent")
+	var calls int64
+	out, errs := Retry(ctx, Chan([]int{1}), func(ctx context.Context, i int) (int, error) {
+		atomic.AddInt64(&calls, 1)
+		return 0, permanent
+	}, RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: time.Millisecond,
+		IsRetryable:  func(error) bool { return false },
+	})
+
+	go func() {
+		for range out {
+		}
+	}()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, permanent) {
+			t.Fatalf("got error %v, want %v", err, permanent)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for an error")
+	}
+	if atomic.LoadInt64(&calls) != 1 {
+		t.Fatalf("got %d calls, want exactly 1 (non-retryable error must not be retried)", calls)
+	}
+}
+
+func TestRetryBacksOffBetweenAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	boom := errors.New("boom")
+	start := time.Now()
+	out, errs := Retry(ctx, Chan([]int{1}), func(ctx context.Context, i int) (int, error) {
+		return 0, boom
+	}, RetryPolicy{MaxAttempts: 3, InitialDelay: 20 * time.Millisecond, Multiplier: 2})
+
+	go func() {
+		for range out {
+		}
+	}()
+	<-errs
+	elapsed := time.Since(start)
+	// Two retries at ~20ms and ~40ms: allow generous slack for scheduling jitter.
+	if elapsed < 40*time.Millisecond {
+		t.Fatalf("elapsed %v, want at least 40ms of backoff between 3 attempts", elapsed)
+	}
+}
+
+func TestRetryHonorsSemaphoreConcurrency(t *testing.T) {
+	const n = 4
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ins := make([]int, 20)
+	var cur, max int64
+	var closeOnce int32
+	release := make(chan struct{})
+
+	out, errs := Retry(ctx, Chan(ins), func(ctx context.Context, i int) (int, error) {
+		blockUntilConcurrent(ctx, n, &cur, &max, release, &closeOnce)
+		return i, nil
+	}, RetryPolicy{MaxAttempts: 1}, WithSemaphore[int](n))
+
+	go func() {
+		for range errs {
+		}
+	}()
+	if _, err := Drain(ctx, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt64(&max); got < n {
+		t.Fatalf("observed max concurrency %d, want at least %d (WithSemaphore is not being honored)", got, n)
+	}
+}